@@ -0,0 +1,156 @@
+/* Copyright 2017 Google Inc.
+ * https://github.com/NeilFraser/CodeCity
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package object
+
+import "testing"
+
+// newDescObject builds a property-descriptor bag object (as passed to
+// DefineProperties/Create) with one own, enumerable, writable,
+// configurable data property per entry in fields.
+func newDescObject(fields map[string]Value) *Object {
+	obj := newTestObject(Null{})
+	for name, v := range fields {
+		obj.properties[name] = newDataProp(v, true, true, true)
+	}
+	return obj
+}
+
+func TestDefinePropertiesUsesOwnAccessorOverInheritedValue(t *testing.T) {
+	// Regression test for test262 15.2.3.7-6-a-8: an own accessor
+	// property named "value" on the descriptor object must be used
+	// even though its prototype has an inherited data property of the
+	// same name.
+	pollutedProto := newTestObject(Null{})
+	pollutedProto.properties["value"] = newDataProp(testValue{100}, true, true, true)
+
+	get := testFunc{call: func(args []Value) (Value, *ErrorMsg) { return testValue{42}, nil }}
+	descObj := newTestObject(pollutedProto)
+	descObj.properties["value"] = property{
+		value:      accessorProperty{get: get},
+		enumerable: true,
+	}
+	descObj.properties["enumerable"] = newDataProp(testValue{1}, true, true, true)
+
+	props := newTestObject(Null{})
+	props.properties["x"] = newDataProp(descObj, true, true, true)
+
+	target := newTestObject(Null{})
+	if err := DefineProperties(target, props, nil); err != nil {
+		t.Fatalf("DefineProperties returned error: %v", err)
+	}
+	v, err := target.GetProperty("x", nil)
+	if err != nil {
+		t.Fatalf("GetProperty(\"x\") returned error: %v", err)
+	}
+	if v != (testValue{42}) {
+		t.Errorf("GetProperty(\"x\") = %v, want testValue{42} (own accessor, not inherited data property)", v)
+	}
+}
+
+func TestDefinePropertiesIgnoresInheritedValue(t *testing.T) {
+	// A "value" inherited from the descriptor object's prototype (e.g.
+	// one polluted onto Object.prototype) must not leak into the
+	// defined property: only own properties of the descriptor object
+	// are consulted.
+	pollutedProto := newTestObject(Null{})
+	pollutedProto.properties["value"] = newDataProp(testValue{100}, true, true, true)
+
+	descObj := newTestObject(pollutedProto)
+	descObj.properties["enumerable"] = newDataProp(testValue{1}, true, true, true)
+
+	props := newTestObject(Null{})
+	props.properties["y"] = newDataProp(descObj, true, true, true)
+
+	target := newTestObject(Null{})
+	if err := DefineProperties(target, props, nil); err != nil {
+		t.Fatalf("DefineProperties returned error: %v", err)
+	}
+	desc, ok, err := target.GetOwnPropertyDescriptor("y", nil)
+	if err != nil {
+		t.Fatalf("GetOwnPropertyDescriptor(\"y\") returned error: %v", err)
+	}
+	if !ok {
+		t.Fatal("target has no own property \"y\" after DefineProperties")
+	}
+	// "y" was defined with only an enumerable attribute, so per ES5
+	// §8.12.9 step 4 it gets the default Value of undefined; the
+	// pollutedProto's "value" must not have leaked in instead.
+	if desc.Value != (Undefined{}) {
+		t.Errorf("Value = %v, want Undefined{} (must not inherit Object.prototype.value = %v)",
+			desc.Value, testValue{100})
+	}
+}
+
+func TestDefinePropertiesIsAllOrNothing(t *testing.T) {
+	target := newTestObject(Null{})
+	target.properties["existing"] = newDataProp(testValue{1}, true, true, false)
+
+	props := newTestObject(Null{})
+	props.properties["existing"] = newDataProp(newDescObject(map[string]Value{
+		"configurable": testValue{1}, // would be rejected: existing is non-configurable
+	}), true, true, true)
+	props.properties["new"] = newDataProp(newDescObject(map[string]Value{
+		"value": testValue{2},
+	}), true, true, true)
+
+	if err := DefineProperties(target, props, nil); err == nil {
+		t.Fatal("DefineProperties did not report the invalid descriptor for \"existing\"")
+	}
+	if target.HasOwnProperty("new") {
+		t.Error("DefineProperties defined \"new\" even though another descriptor in the batch was invalid")
+	}
+}
+
+func TestCreateDefinesOwnPropertiesAndPrototype(t *testing.T) {
+	proto := newTestObject(Null{})
+
+	props := newTestObject(Null{})
+	props.properties["x"] = newDataProp(newDescObject(map[string]Value{
+		"value":      testValue{3},
+		"enumerable": testValue{1},
+	}), true, true, true)
+
+	obj, err := Create(proto, props, nil)
+	if err != nil {
+		t.Fatalf("Create returned error: %v", err)
+	}
+	if obj.Parent() != Value(proto) {
+		t.Errorf("Parent() = %v, want proto", obj.Parent())
+	}
+	v, err := obj.GetProperty("x", nil)
+	if err != nil {
+		t.Fatalf("GetProperty(\"x\") returned error: %v", err)
+	}
+	if v != (testValue{3}) {
+		t.Errorf("GetProperty(\"x\") = %v, want testValue{3}", v)
+	}
+}
+
+func TestCreateWithNilProperties(t *testing.T) {
+	proto := newTestObject(Null{})
+
+	obj, err := Create(proto, nil, nil)
+	if err != nil {
+		t.Fatalf("Create returned error: %v", err)
+	}
+	if obj.Parent() != Value(proto) {
+		t.Errorf("Parent() = %v, want proto", obj.Parent())
+	}
+	if len(obj.EnumerableOwnPropertyNames()) != 0 {
+		t.Error("Create with nil properties produced own properties")
+	}
+}
@@ -0,0 +1,391 @@
+/* Copyright 2017 Google Inc.
+ * https://github.com/NeilFraser/CodeCity
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package object
+
+// PropertyDescriptor describes a property in the manner of ES5
+// Object.defineProperty / Object.getOwnPropertyDescriptor.  For a data
+// descriptor, Value and Writable apply; for an accessor descriptor,
+// Get and Set apply; the two forms are mutually exclusive.
+//
+// Because DefineProperty treats a descriptor as a partial update (any
+// field not present is left unchanged on an existing property, or
+// defaulted per ES5 §8.12.9 step 4 on a new one), each field has a
+// corresponding Has* flag recording whether it was actually specified.
+type PropertyDescriptor struct {
+	Value Value
+	Get   Callable
+	Set   Callable
+
+	Writable     bool
+	Enumerable   bool
+	Configurable bool
+
+	HasValue        bool
+	HasWritable     bool
+	HasGet          bool
+	HasSet          bool
+	HasEnumerable   bool
+	HasConfigurable bool
+}
+
+// isAccessorDesc reports whether desc specifies an accessor
+// descriptor (i.e. a Get and/or Set was supplied).
+func (desc PropertyDescriptor) isAccessorDesc() bool {
+	return desc.HasGet || desc.HasSet
+}
+
+// isDataDesc reports whether desc specifies a data descriptor (i.e. a
+// Value and/or Writable was supplied).
+func (desc PropertyDescriptor) isDataDesc() bool {
+	return desc.HasValue || desc.HasWritable
+}
+
+// GetOwnPropertyDescriptor returns the descriptor for the named own
+// property, per ES5 §8.10.4 FromPropertyDescriptor (minus the JS
+// object wrapping, which is the interpreter's job).  perm identifies
+// the acting owner, for permission checking: it returns a
+// PermissionError if perm may not read the property.
+func (this Object) GetOwnPropertyDescriptor(name string, perm *Owner) (PropertyDescriptor, bool, *ErrorMsg) {
+	pd, ok := this.properties[name]
+	if !ok {
+		return PropertyDescriptor{}, false, nil
+	}
+	if !canRead(pd, perm) {
+		return PropertyDescriptor{}, false, newError("PermissionError",
+			"Permission denied to read property "+name)
+	}
+	desc := PropertyDescriptor{
+		Enumerable:      pd.enumerable,
+		HasEnumerable:   true,
+		Configurable:    pd.configurable,
+		HasConfigurable: true,
+	}
+	switch v := pd.value.(type) {
+	case dataProperty:
+		desc.Value, desc.HasValue = v.v, true
+		desc.Writable, desc.HasWritable = v.writable, true
+	case accessorProperty:
+		desc.Get, desc.HasGet = v.get, true
+		desc.Set, desc.HasSet = v.set, true
+	}
+	return desc, true, nil
+}
+
+// DefineProperty creates or alters the named own property per ES5
+// §8.12.9 (via Object.defineProperty).  perm identifies the acting
+// owner, for permission checking.  It returns an ErrorMsg if desc is
+// incompatible with the existing (non-configurable) property, if name
+// does not already exist and this object is not extensible, or if
+// perm lacks permission to make the change.
+func (this *Object) DefineProperty(name string, desc PropertyDescriptor, perm *Owner) *ErrorMsg {
+	if err := this.validateDefineProperty(name, desc, perm); err != nil {
+		return err
+	}
+	this.applyDefineProperty(name, desc, perm)
+	return nil
+}
+
+// validateDefineProperty checks whether DefineProperty(name, desc,
+// perm) would succeed, without mutating this object.  It is split out
+// from DefineProperty so that DefineProperties can validate a whole
+// batch of descriptors before applying any of them.
+func (this *Object) validateDefineProperty(name string, desc PropertyDescriptor, perm *Owner) *ErrorMsg {
+	old, exists := this.properties[name]
+	if !exists {
+		if !this.canCreate(perm) {
+			return newError("PermissionError",
+				"Permission denied to create property "+name)
+		}
+		if !this.extensible {
+			return newError("TypeError",
+				"Cannot define property "+name+", object is not extensible")
+		}
+		return nil
+	}
+	if !canWrite(old, perm) {
+		return newError("PermissionError",
+			"Permission denied to redefine property "+name)
+	}
+	if !old.configurable {
+		return checkRedefine(name, old, desc)
+	}
+	return nil
+}
+
+// applyDefineProperty creates or merges the named property per desc,
+// assuming validateDefineProperty(name, desc, perm) has already
+// succeeded.  A newly created property is owned by perm, unless an
+// inherited property of the same name has i set, in which case the
+// ancestor's owner is preserved (matching SetProperty's behaviour).
+func (this *Object) applyDefineProperty(name string, desc PropertyDescriptor, perm *Owner) {
+	old, exists := this.properties[name]
+	if !exists {
+		owner := perm
+		// TODO(chunk0-5): ipd.i is currently always false; see the
+		// matching TODO in SetProperty (object.go) for why this branch
+		// is presently dead.
+		if ipd, found := this.inheritedProperty(name); found && ipd.i {
+			owner = ipd.owner
+		}
+		this.properties[name] = property{
+			owner:        owner,
+			value:        descriptorValue(desc),
+			r:            true, // world-readable, matching SetProperty's new-property default
+			enumerable:   desc.Enumerable,
+			configurable: desc.Configurable,
+		}
+		return
+	}
+	this.properties[name] = mergeDescriptor(old, desc)
+}
+
+// checkRedefine implements the "reject" tests of ES5 §8.12.9 steps
+// 7-11, which apply only when the existing property is not
+// configurable.
+func checkRedefine(name string, old property, desc PropertyDescriptor) *ErrorMsg {
+	reject := newError("TypeError", "Cannot redefine non-configurable property "+name)
+	if desc.HasConfigurable && desc.Configurable {
+		return reject
+	}
+	if desc.HasEnumerable && desc.Enumerable != old.enumerable {
+		return reject
+	}
+	odp, oldIsData := old.value.(dataProperty)
+	switch {
+	case desc.isDataDesc() && !oldIsData:
+		return reject
+	case desc.isAccessorDesc() && oldIsData:
+		return reject
+	case oldIsData:
+		if !odp.writable {
+			if desc.HasWritable && desc.Writable {
+				return reject
+			}
+			if desc.HasValue && desc.Value != odp.v {
+				return reject
+			}
+		}
+	default:
+		oap := old.value.(accessorProperty)
+		if desc.HasGet && desc.Get != oap.get {
+			return reject
+		}
+		if desc.HasSet && desc.Set != oap.set {
+			return reject
+		}
+	}
+	return nil
+}
+
+// mergeDescriptor applies the fields present in desc on top of old,
+// leaving any unspecified attribute (or, for a same-kind descriptor,
+// value/getter/setter) unchanged, per ES5 §8.12.9 step 9.
+func mergeDescriptor(old property, desc PropertyDescriptor) property {
+	p := old
+	if desc.HasEnumerable {
+		p.enumerable = desc.Enumerable
+	}
+	if desc.HasConfigurable {
+		p.configurable = desc.Configurable
+	}
+	switch v := p.value.(type) {
+	case dataProperty:
+		if desc.isAccessorDesc() {
+			p.value = descriptorValue(desc)
+			break
+		}
+		if desc.HasValue {
+			v.v = desc.Value
+		}
+		if desc.HasWritable {
+			v.writable = desc.Writable
+		}
+		p.value = v
+	case accessorProperty:
+		if desc.isDataDesc() {
+			p.value = descriptorValue(desc)
+			break
+		}
+		if desc.HasGet {
+			v.get = desc.Get
+		}
+		if desc.HasSet {
+			v.set = desc.Set
+		}
+		p.value = v
+	}
+	return p
+}
+
+// descriptorValue builds the propertyValue (data or accessor) implied
+// by a from-scratch descriptor, defaulting any unspecified field to
+// its ES5 zero value (ES5 §8.12.9 step 4).
+func descriptorValue(desc PropertyDescriptor) propertyValue {
+	if desc.isAccessorDesc() {
+		return accessorProperty{get: desc.Get, set: desc.Set}
+	}
+	v := desc.Value
+	if !desc.HasValue {
+		v = Undefined{}
+	}
+	return dataProperty{v: v, writable: desc.Writable}
+}
+
+// ownPropertyValue returns the value of obj's own property called
+// name (invoking its getter if it is an accessor property).  Unlike
+// GetProperty, it never consults obj's prototype chain: the caller is
+// extracting descriptor fields (value, get, set, etc.) and an
+// inherited "value" or "get" property (e.g. one added to
+// Object.prototype) must not be mistaken for one actually present on
+// the descriptor object itself.  perm identifies the acting owner, for
+// permission checking.
+func ownPropertyValue(obj *Object, name string, perm *Owner) (Value, *ErrorMsg, bool) {
+	pd, ok := obj.properties[name]
+	if !ok {
+		return nil, nil, false
+	}
+	if !canRead(pd, perm) {
+		return nil, newError("PermissionError",
+			"Permission denied to read property "+name), true
+	}
+	switch v := pd.value.(type) {
+	case dataProperty:
+		return v.v, nil, true
+	case accessorProperty:
+		if v.get == nil {
+			return Undefined{}, nil, true
+		}
+		val, err := v.get.Call(nil)
+		return val, err, true
+	}
+	return nil, nil, false
+}
+
+// toPropertyDescriptor converts obj's own "value", "writable", "get",
+// "set", "enumerable" and "configurable" properties into a
+// PropertyDescriptor, per ES5 §8.10.5 ToPropertyDescriptor (but
+// consulting only obj's own properties; see ownPropertyValue).  perm
+// identifies the acting owner, for permission checking.
+func toPropertyDescriptor(obj *Object, perm *Owner) (PropertyDescriptor, *ErrorMsg) {
+	var desc PropertyDescriptor
+	if v, err, ok := ownPropertyValue(obj, "value", perm); ok {
+		if err != nil {
+			return desc, err
+		}
+		desc.Value, desc.HasValue = v, true
+	}
+	if v, err, ok := ownPropertyValue(obj, "writable", perm); ok {
+		if err != nil {
+			return desc, err
+		}
+		desc.Writable, desc.HasWritable = ToBoolean(v), true
+	}
+	if v, err, ok := ownPropertyValue(obj, "get", perm); ok {
+		if err != nil {
+			return desc, err
+		}
+		get, isCallable := v.(Callable)
+		if !isCallable {
+			return desc, newError("TypeError", "Getter must be a function")
+		}
+		desc.Get, desc.HasGet = get, true
+	}
+	if v, err, ok := ownPropertyValue(obj, "set", perm); ok {
+		if err != nil {
+			return desc, err
+		}
+		set, isCallable := v.(Callable)
+		if !isCallable {
+			return desc, newError("TypeError", "Setter must be a function")
+		}
+		desc.Set, desc.HasSet = set, true
+	}
+	if v, err, ok := ownPropertyValue(obj, "enumerable", perm); ok {
+		if err != nil {
+			return desc, err
+		}
+		desc.Enumerable, desc.HasEnumerable = ToBoolean(v), true
+	}
+	if v, err, ok := ownPropertyValue(obj, "configurable", perm); ok {
+		if err != nil {
+			return desc, err
+		}
+		desc.Configurable, desc.HasConfigurable = ToBoolean(v), true
+	}
+	if desc.isDataDesc() && desc.isAccessorDesc() {
+		return desc, newError("TypeError",
+			"Property descriptor cannot have both accessors and a value or writable attribute")
+	}
+	return desc, nil
+}
+
+// DefineProperties implements the core of Object.defineProperties (and
+// hence the second argument of Object.create): it converts each of
+// props's enumerable own properties into a PropertyDescriptor and
+// defines the corresponding property on target.  Application is
+// all-or-nothing: every descriptor is extracted and validated before
+// any of them are applied, so a single invalid descriptor leaves
+// target completely unchanged.
+func DefineProperties(target *Object, props Value, perm *Owner) *ErrorMsg {
+	propsObj, ok := props.(*Object)
+	if !ok {
+		return newError("TypeError", "Properties must be an object")
+	}
+	names := propsObj.EnumerableOwnPropertyNames()
+	descs := make([]PropertyDescriptor, len(names))
+	for i, name := range names {
+		descVal, err := propsObj.GetProperty(name, perm)
+		if err != nil {
+			return err
+		}
+		descObj, ok := descVal.(*Object)
+		if !ok {
+			return newError("TypeError", "Property description must be an object: "+name)
+		}
+		desc, err := toPropertyDescriptor(descObj, perm)
+		if err != nil {
+			return err
+		}
+		if err := target.validateDefineProperty(name, desc, perm); err != nil {
+			return err
+		}
+		descs[i] = desc
+	}
+	for i, name := range names {
+		target.applyDefineProperty(name, descs[i], perm)
+	}
+	return nil
+}
+
+// Create implements Object.create(proto, properties): it returns a new
+// object, owned by perm, with the given prototype and, if properties
+// is non-nil, with own properties defined from it via DefineProperties.
+func Create(proto Value, properties Value, perm *Owner) (*Object, *ErrorMsg) {
+	obj := &Object{
+		owner:      perm,
+		parent:     proto,
+		properties: make(map[string]property),
+		extensible: true,
+	}
+	if properties == nil {
+		return obj, nil
+	}
+	if err := DefineProperties(obj, properties, perm); err != nil {
+		return nil, err
+	}
+	return obj, nil
+}
@@ -0,0 +1,148 @@
+/* Copyright 2017 Google Inc.
+ * https://github.com/NeilFraser/CodeCity
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package object
+
+import "testing"
+
+// testValue is a minimal comparable Value, used where descriptor tests
+// need two distinguishable values (Undefined{} can't serve, since all
+// Undefined{} are equal to each other).
+type testValue struct{ n int }
+
+func (testValue) Type() string      { return "object" }
+func (testValue) IsPrimitive() bool { return true }
+func (testValue) Parent() Value     { return Null{} }
+
+func (testValue) GetProperty(name string, perm *Owner) (Value, *ErrorMsg) {
+	return Undefined{}, nil
+}
+
+func (testValue) SetProperty(name string, v Value, perm *Owner) *ErrorMsg {
+	return nil
+}
+
+var _ Value = testValue{}
+
+func newDataProp(v Value, writable, enumerable, configurable bool) property {
+	return property{
+		value:        dataProperty{v: v, writable: writable},
+		enumerable:   enumerable,
+		configurable: configurable,
+	}
+}
+
+func TestDefinePropertyRejectsConfigurableChange(t *testing.T) {
+	obj := newTestObject(Null{})
+	obj.properties["foo"] = newDataProp(testValue{1}, true, true, false)
+
+	err := obj.DefineProperty("foo", PropertyDescriptor{
+		Configurable: true, HasConfigurable: true,
+	}, nil)
+	if err == nil {
+		t.Fatal("DefineProperty did not reject making a non-configurable property configurable")
+	}
+}
+
+func TestDefinePropertyAllowsWritableToNonWritable(t *testing.T) {
+	obj := newTestObject(Null{})
+	obj.properties["foo"] = newDataProp(testValue{1}, true, true, false)
+
+	err := obj.DefineProperty("foo", PropertyDescriptor{
+		Writable: false, HasWritable: true,
+	}, nil)
+	if err != nil {
+		t.Fatalf("DefineProperty rejected writable->non-writable change: %v", err)
+	}
+	desc, _, err2 := obj.GetOwnPropertyDescriptor("foo", nil)
+	if err2 != nil {
+		t.Fatalf("GetOwnPropertyDescriptor(\"foo\") returned error: %v", err2)
+	}
+	if desc.Writable {
+		t.Error("property is still writable after DefineProperty set writable:false")
+	}
+}
+
+func TestDefinePropertyRejectsNonWritableToWritable(t *testing.T) {
+	obj := newTestObject(Null{})
+	obj.properties["foo"] = newDataProp(testValue{1}, false, true, false)
+
+	err := obj.DefineProperty("foo", PropertyDescriptor{
+		Writable: true, HasWritable: true,
+	}, nil)
+	if err == nil {
+		t.Fatal("DefineProperty did not reject non-writable->writable change on a non-configurable property")
+	}
+}
+
+func TestDefinePropertyRejectsValueChangeWhenNonWritable(t *testing.T) {
+	obj := newTestObject(Null{})
+	obj.properties["foo"] = newDataProp(testValue{1}, false, true, false)
+
+	err := obj.DefineProperty("foo", PropertyDescriptor{
+		Value: testValue{2}, HasValue: true,
+	}, nil)
+	if err == nil {
+		t.Fatal("DefineProperty did not reject a value change on a non-writable, non-configurable property")
+	}
+}
+
+func TestDefinePropertyAllowsValueChangeWhenWritable(t *testing.T) {
+	obj := newTestObject(Null{})
+	obj.properties["foo"] = newDataProp(testValue{1}, true, true, false)
+
+	err := obj.DefineProperty("foo", PropertyDescriptor{
+		Value: testValue{2}, HasValue: true,
+	}, nil)
+	if err != nil {
+		t.Fatalf("DefineProperty rejected a value change on a writable property: %v", err)
+	}
+	desc, _, err2 := obj.GetOwnPropertyDescriptor("foo", nil)
+	if err2 != nil {
+		t.Fatalf("GetOwnPropertyDescriptor(\"foo\") returned error: %v", err2)
+	}
+	if desc.Value != (testValue{2}) {
+		t.Errorf("Value = %v, want testValue{2}", desc.Value)
+	}
+}
+
+func TestDefinePropertyRejectsDataToAccessorWhenNonConfigurable(t *testing.T) {
+	obj := newTestObject(Null{})
+	obj.properties["foo"] = newDataProp(testValue{1}, true, true, false)
+
+	get := testFunc{call: func(args []Value) (Value, *ErrorMsg) { return Undefined{}, nil }}
+	err := obj.DefineProperty("foo", PropertyDescriptor{Get: get, HasGet: true}, nil)
+	if err == nil {
+		t.Fatal("DefineProperty did not reject switching a non-configurable data property to an accessor")
+	}
+}
+
+func TestDefinePropertyAllowsDataToAccessorWhenConfigurable(t *testing.T) {
+	obj := newTestObject(Null{})
+	obj.properties["foo"] = newDataProp(testValue{1}, true, true, true)
+
+	get := testFunc{call: func(args []Value) (Value, *ErrorMsg) { return testValue{9}, nil }}
+	if err := obj.DefineProperty("foo", PropertyDescriptor{Get: get, HasGet: true}, nil); err != nil {
+		t.Fatalf("DefineProperty rejected switching a configurable data property to an accessor: %v", err)
+	}
+	v, err := obj.GetProperty("foo", nil)
+	if err != nil {
+		t.Fatalf("GetProperty returned error: %v", err)
+	}
+	if v != (testValue{9}) {
+		t.Errorf("GetProperty = %v, want testValue{9}", v)
+	}
+}
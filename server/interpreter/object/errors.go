@@ -0,0 +1,48 @@
+/* Copyright 2017 Google Inc.
+ * https://github.com/NeilFraser/CodeCity
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package object
+
+// ErrorMsg represents a JavaScript error to be thrown back into the
+// interpreter (e.g. by a failed property access) when an operation
+// cannot be completed as requested.
+type ErrorMsg struct {
+	name    string // Name of the JavaScript error constructor (e.g. "TypeError").
+	message string
+}
+
+// Error implements the error interface.  Interpreter code should
+// generally prefer Name()/Message() (or construct the corresponding JS
+// Error object) over this string form.
+func (e *ErrorMsg) Error() string {
+	return e.name + ": " + e.message
+}
+
+// Name returns the JavaScript error constructor name (e.g. "TypeError").
+func (e *ErrorMsg) Name() string {
+	return e.name
+}
+
+// Message returns the human-readable error message.
+func (e *ErrorMsg) Message() string {
+	return e.message
+}
+
+// newError returns an ErrorMsg for the named JavaScript error
+// constructor with the given message.
+func newError(name, message string) *ErrorMsg {
+	return &ErrorMsg{name: name, message: message}
+}
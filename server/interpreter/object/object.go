@@ -32,12 +32,23 @@ type Value interface {
 	Parent() Value
 
 	// GetProperty returns the current value of the given property or
-	// an ErrorMsg if that was not possible.
-	GetProperty(name string) (Value, *ErrorMsg)
+	// an ErrorMsg if that was not possible.  perm identifies the
+	// acting owner, for permission checking.
+	GetProperty(name string, perm *Owner) (Value, *ErrorMsg)
 
 	// SetProperty sets the given property to the specified value or
-	// returns an ErrorMsg if that was not possible.
-	SetProperty(name string, value Value) *ErrorMsg
+	// returns an ErrorMsg if that was not possible.  perm identifies
+	// the acting owner, for permission checking.
+	SetProperty(name string, value Value, perm *Owner) *ErrorMsg
+}
+
+// Callable is implemented by values which can be invoked as functions
+// (typically closures created by the interpreter).  It lets this
+// package invoke accessor getters and setters without needing to
+// import the interpreter package that implements them.
+type Callable interface {
+	Value
+	Call(args []Value) (Value, *ErrorMsg)
 }
 
 // Object represents typical JavaScript objects with (optional)
@@ -46,23 +57,52 @@ type Object struct {
 	owner      *Owner
 	parent     Value
 	properties map[string]property
-	f          bool
+	extensible bool // may own properties be added to this object?
 }
 
 // property is a property descriptor, with the following fields:
-// owner: Who owns the property (has permission to write it)?
-// v:     The actual value of the property.
-// r:     Is the property world-readable?
-// e:     Is the property enumerable
-// i:     Is the property ownership inherited on children?
+// owner:        Who owns the property (has permission to write it)?
+// value:        The actual value of the property: a dataProperty or
+//               an accessorProperty.
+// r:            Is the property world-readable?
+// i:            Is the property ownership inherited on children?
+// enumerable:   ES5 [[Enumerable]] attribute.
+// configurable: ES5 [[Configurable]] attribute.
 type property struct {
-	owner *Owner
-	v     Value
-	r     bool
-	e     bool
-	i     bool
+	owner        *Owner
+	value        propertyValue
+	r            bool
+	i            bool
+	enumerable   bool
+	configurable bool
+}
+
+// propertyValue is the part of a property descriptor that determines
+// how a property is read and written.  It is implemented by
+// dataProperty (a plain value) and accessorProperty (a getter/setter
+// pair); exactly one of the two applies to any given property.
+type propertyValue interface {
+	isPropertyValue()
+}
+
+// dataProperty is a property whose value is read and written directly.
+type dataProperty struct {
+	v        Value
+	writable bool // ES5 [[Writable]] attribute.
 }
 
+// accessorProperty is a property backed by getter/setter functions, as
+// created by (for example) Object.defineProperty(obj, name, {get, set}).
+// Either of get or set may be nil: reading through a nil get yields
+// undefined, and writing through a nil set fails with a TypeError.
+type accessorProperty struct {
+	get Callable
+	set Callable
+}
+
+func (dataProperty) isPropertyValue()     {}
+func (accessorProperty) isPropertyValue() {}
+
 // *Object must satisfy Value.
 var _ Value = (*Object)(nil)
 
@@ -78,39 +118,170 @@ func (this Object) Parent() Value {
 	return this.parent
 }
 
-func (this Object) GetProperty(name string) (Value, *ErrorMsg) {
+func (this Object) GetProperty(name string, perm *Owner) (Value, *ErrorMsg) {
 	pd, ok := this.properties[name]
-	// FIXME: permissions check for property readability goes here
 	if !ok {
+		if parent, isObj := this.Parent().(*Object); isObj {
+			return parent.GetProperty(name, perm)
+		}
+		return Undefined{}, nil
+	}
+	if !canRead(pd, perm) {
+		return nil, newError("PermissionError",
+			"Permission denied to read property "+name)
+	}
+	switch v := pd.value.(type) {
+	case dataProperty:
+		return v.v, nil
+	case accessorProperty:
+		if v.get == nil {
+			return Undefined{}, nil
+		}
+		return v.get.Call(nil)
+	default:
 		return Undefined{}, nil
 	}
-	return pd.v, nil
 }
 
-func (this *Object) SetProperty(name string, value Value) *ErrorMsg {
+func (this *Object) SetProperty(name string, value Value, perm *Owner) *ErrorMsg {
 	pd, ok := this.properties[name]
 	if ok { // Updating existing property
-		// FIXME: permissions check for property writeability goes here
-		pd.v = value
-		this.properties[name] = pd
-		return nil
-	} else { // Creating new property
-		// FIXME: permissions check for object writability goes here
-		this.properties[name] = property{
-			owner: this.owner, // FIXME: should be caller
-			v:     value,
-			r:     true,
-			e:     true,
-			i:     false,
+		if !canWrite(pd, perm) {
+			return newError("PermissionError",
+				"Permission denied to set property "+name)
+		}
+		switch v := pd.value.(type) {
+		case accessorProperty:
+			if v.set == nil {
+				return newError("TypeError",
+					"Cannot set property "+name+" which has only a getter")
+			}
+			_, err := v.set.Call([]Value{value})
+			return err
+		case dataProperty:
+			if !v.writable {
+				return newError("TypeError",
+					"Cannot assign to read only property "+name)
+			}
+			pd.value = dataProperty{v: value, writable: v.writable}
+			this.properties[name] = pd
+			return nil
 		}
 		return nil
 	}
+	// name is not an own property: an inherited accessor property must
+	// have its setter invoked rather than being shadowed.  An inherited
+	// data property, on the other hand, does not prevent the creation
+	// of a new own property below (ES5 §8.12.5 steps 4-5).
+	if parent, isObj := this.Parent().(*Object); isObj {
+		if ipd, found := parent.inheritedProperty(name); found {
+			if apd, isAccessor := ipd.value.(accessorProperty); isAccessor {
+				if !canWrite(ipd, perm) {
+					return newError("PermissionError",
+						"Permission denied to set property "+name)
+				}
+				if apd.set == nil {
+					return newError("TypeError",
+						"Cannot set property "+name+" which has only a getter")
+				}
+				_, err := apd.set.Call([]Value{value})
+				return err
+			}
+		}
+	}
+	// Creating new property
+	if !this.canCreate(perm) {
+		return newError("PermissionError",
+			"Permission denied to create property "+name)
+	}
+	if !this.extensible {
+		return newError("TypeError",
+			"Cannot add property "+name+", object is not extensible")
+	}
+	owner := perm
+	// TODO(chunk0-5): ipd.i is currently always false; nothing yet sets
+	// it to true (no PropertyDescriptor field or helper exposes it), so
+	// this branch is presently dead. Kept to match the pre-existing
+	// baseline field and ready for when ownership inheritance is wired
+	// up.
+	if ipd, found := this.inheritedProperty(name); found && ipd.i {
+		owner = ipd.owner
+	}
+	this.properties[name] = property{
+		owner:        owner,
+		value:        dataProperty{v: value, writable: true},
+		r:            true,
+		i:            false,
+		enumerable:   true,
+		configurable: true,
+	}
+	return nil
+}
+
+// PreventExtensions marks this object as non-extensible: no further
+// own properties may be added to it (ES5 §15.2.3.10).  Existing
+// properties are unaffected; combine with DefineProperty calls that
+// mark them non-writable/non-configurable to fully seal or freeze it.
+func (this *Object) PreventExtensions() {
+	this.extensible = false
+}
+
+// IsExtensible reports whether new own properties may be added to
+// this object.
+func (this Object) IsExtensible() bool {
+	return this.extensible
+}
+
+// inheritedProperty walks the prototype chain starting at this and
+// returns the first property found with the given name.
+func (this *Object) inheritedProperty(name string) (property, bool) {
+	if pd, ok := this.properties[name]; ok {
+		return pd, true
+	}
+	if parent, isObj := this.Parent().(*Object); isObj {
+		return parent.inheritedProperty(name)
+	}
+	return property{}, false
+}
+
+// HasOwnProperty reports whether this object has an own property with
+// the given name, ignoring the prototype chain.
+func (this Object) HasOwnProperty(name string) bool {
+	_, ok := this.properties[name]
+	return ok
+}
+
+// HasProperty reports whether this object has a property with the
+// given name, considering the prototype chain.
+func (this Object) HasProperty(name string) bool {
+	if this.HasOwnProperty(name) {
+		return true
+	}
+	if parent, isObj := this.Parent().(*Object); isObj {
+		return parent.HasProperty(name)
+	}
+	return false
+}
+
+// EnumerableOwnPropertyNames returns the names of this object's own
+// enumerable properties, in unspecified order, as used by for-in,
+// Object.keys, and DefineProperties.
+func (this Object) EnumerableOwnPropertyNames() []string {
+	names := make([]string, 0, len(this.properties))
+	for name, pd := range this.properties {
+		if pd.enumerable {
+			names = append(names, name)
+		}
+	}
+	return names
 }
 
 // ObjectProto is the default prototype for (plain) JavaScript objects
 // (i.e., ones created from object literals and not via
 // Object.create(nil)).
 var ObjectProto = &Object{
+	owner:      Root,
 	parent:     Null{},
 	properties: make(map[string]property),
+	extensible: true,
 }
\ No newline at end of file
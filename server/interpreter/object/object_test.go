@@ -0,0 +1,120 @@
+/* Copyright 2017 Google Inc.
+ * https://github.com/NeilFraser/CodeCity
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package object
+
+import "testing"
+
+// testFunc is a minimal Callable, used to exercise accessor properties
+// in tests without depending on the interpreter package.
+type testFunc struct {
+	call func(args []Value) (Value, *ErrorMsg)
+}
+
+func (testFunc) Type() string      { return "function" }
+func (testFunc) IsPrimitive() bool { return false }
+func (testFunc) Parent() Value     { return Null{} }
+
+func (testFunc) GetProperty(name string, perm *Owner) (Value, *ErrorMsg) {
+	return Undefined{}, nil
+}
+
+func (testFunc) SetProperty(name string, v Value, perm *Owner) *ErrorMsg {
+	return nil
+}
+
+func (t testFunc) Call(args []Value) (Value, *ErrorMsg) {
+	return t.call(args)
+}
+
+var _ Callable = testFunc{}
+
+func newTestObject(parent Value) *Object {
+	return &Object{
+		parent:     parent,
+		properties: make(map[string]property),
+		extensible: true,
+	}
+}
+
+func TestGetPropertyInvokesInheritedGetter(t *testing.T) {
+	called := false
+	base := newTestObject(Null{})
+	base.properties["foo"] = property{
+		value: accessorProperty{
+			get: testFunc{call: func(args []Value) (Value, *ErrorMsg) {
+				called = true
+				return Undefined{}, nil
+			}},
+		},
+		enumerable: true,
+	}
+	child := newTestObject(base)
+
+	if child.HasOwnProperty("foo") {
+		t.Error("HasOwnProperty(\"foo\") = true, want false: property is inherited")
+	}
+	if !child.HasProperty("foo") {
+		t.Error("HasProperty(\"foo\") = false, want true: property is inherited")
+	}
+	if _, err := child.GetProperty("foo", nil); err != nil {
+		t.Fatalf("GetProperty(\"foo\") returned error: %v", err)
+	}
+	if !called {
+		t.Error("GetProperty(\"foo\") did not invoke the inherited getter")
+	}
+}
+
+func TestSetPropertyInvokesInheritedSetter(t *testing.T) {
+	var got Value
+	base := newTestObject(Null{})
+	base.properties["foo"] = property{
+		value: accessorProperty{
+			set: testFunc{call: func(args []Value) (Value, *ErrorMsg) {
+				got = args[0]
+				return Undefined{}, nil
+			}},
+		},
+		enumerable: true,
+	}
+	child := newTestObject(base)
+
+	if err := child.SetProperty("foo", Undefined{}, nil); err != nil {
+		t.Fatalf("SetProperty(\"foo\", ...) returned error: %v", err)
+	}
+	if got == nil {
+		t.Error("SetProperty(\"foo\", ...) did not invoke the inherited setter")
+	}
+	if child.HasOwnProperty("foo") {
+		t.Error("SetProperty via inherited accessor unexpectedly created an own property")
+	}
+}
+
+func TestSetPropertyShadowsInheritedDataProperty(t *testing.T) {
+	base := newTestObject(Null{})
+	base.properties["foo"] = property{
+		value:      dataProperty{v: Undefined{}},
+		enumerable: true,
+	}
+	child := newTestObject(base)
+
+	if err := child.SetProperty("foo", Undefined{}, nil); err != nil {
+		t.Fatalf("SetProperty(\"foo\", ...) returned error: %v", err)
+	}
+	if !child.HasOwnProperty("foo") {
+		t.Error("SetProperty did not create an own property shadowing the inherited data property")
+	}
+}
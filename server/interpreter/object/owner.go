@@ -0,0 +1,78 @@
+/* Copyright 2017 Google Inc.
+ * https://github.com/NeilFraser/CodeCity
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package object
+
+// Owner identifies a principal (typically a CodeCity user) that may
+// own objects and properties, for the purposes of the ownership-based
+// permission system enforced by GetProperty/SetProperty/DefineProperty
+// and friends.  Two Owners are the same principal iff they are the
+// same pointer.
+type Owner struct {
+	name string
+}
+
+// NewOwner returns a new Owner, distinct from all others, identified
+// (for debugging purposes only) by name.
+func NewOwner(name string) *Owner {
+	return &Owner{name: name}
+}
+
+// String returns the owner's name, for debugging and logging.
+func (o *Owner) String() string {
+	if o == nil {
+		return "<no owner>"
+	}
+	return o.name
+}
+
+// Root is the distinguished Owner that bypasses all ownership checks,
+// analogous to root on a Unix system.
+var Root = NewOwner("root")
+
+// GetOwner returns the owner of this object (who, like Root, may create
+// new own properties on it), or nil if it has none.  Writing an
+// existing property is governed by that property's own owner.
+func (this Object) GetOwner() *Owner {
+	return this.owner
+}
+
+// SetOwner changes the owner of this object.  It performs no
+// permission check of its own; callers are responsible for ensuring
+// that the acting owner is authorized to make the change (typically by
+// requiring it to be Root or this.GetOwner()).
+func (this *Object) SetOwner(owner *Owner) {
+	this.owner = owner
+}
+
+// canRead reports whether perm may read a property with descriptor pd:
+// world-readable properties may be read by anyone, and an owner (or
+// root) may always read its own properties.
+func canRead(pd property, perm *Owner) bool {
+	return pd.r || perm == pd.owner || perm == Root
+}
+
+// canWrite reports whether perm may write (or redefine) an existing
+// property with descriptor pd.
+func canWrite(pd property, perm *Owner) bool {
+	return perm == pd.owner || perm == Root
+}
+
+// canCreate reports whether perm may create a new own property on
+// this object.
+func (this Object) canCreate(perm *Owner) bool {
+	return perm == this.owner || perm == Root
+}
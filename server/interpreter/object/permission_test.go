@@ -0,0 +1,96 @@
+/* Copyright 2017 Google Inc.
+ * https://github.com/NeilFraser/CodeCity
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package object
+
+import "testing"
+
+func TestGetOwnPropertyDescriptorRejectsUnreadableProperty(t *testing.T) {
+	ownerA, ownerB := NewOwner("a"), NewOwner("b")
+	obj := newTestObject(Null{})
+	obj.properties["secret"] = property{
+		owner: ownerA,
+		value: dataProperty{v: testValue{1}, writable: true},
+		r:     false,
+	}
+
+	if _, _, err := obj.GetOwnPropertyDescriptor("secret", ownerB); err == nil {
+		t.Fatal("GetOwnPropertyDescriptor did not reject a non-owner reading a non-world-readable property")
+	}
+	desc, ok, err := obj.GetOwnPropertyDescriptor("secret", ownerA)
+	if err != nil {
+		t.Fatalf("GetOwnPropertyDescriptor by owner returned error: %v", err)
+	}
+	if !ok || desc.Value != (testValue{1}) {
+		t.Errorf("GetOwnPropertyDescriptor by owner = %v, %v, want testValue{1}, true", desc, ok)
+	}
+}
+
+func TestSetPropertyRejectsInheritedSetterWithoutPermission(t *testing.T) {
+	ownerA, ownerB := NewOwner("a"), NewOwner("b")
+	called := false
+	base := newTestObject(Null{})
+	base.properties["foo"] = property{
+		owner: ownerA,
+		value: accessorProperty{
+			set: testFunc{call: func(args []Value) (Value, *ErrorMsg) {
+				called = true
+				return Undefined{}, nil
+			}},
+		},
+		enumerable: true,
+	}
+	child := newTestObject(base)
+
+	if err := child.SetProperty("foo", Undefined{}, ownerB); err == nil {
+		t.Fatal("SetProperty did not reject invoking an inherited setter without permission")
+	}
+	if called {
+		t.Error("SetProperty invoked the inherited setter despite lacking permission")
+	}
+	if err := child.SetProperty("foo", Undefined{}, ownerA); err != nil {
+		t.Fatalf("SetProperty by the setter's owner returned error: %v", err)
+	}
+	if !called {
+		t.Error("SetProperty did not invoke the inherited setter for its owner")
+	}
+}
+
+func TestDefinePropertiesRejectsUnreadableDescriptorField(t *testing.T) {
+	ownerA, ownerB := NewOwner("a"), NewOwner("b")
+	descObj := newTestObject(Null{})
+	descObj.properties["value"] = property{
+		owner: ownerA,
+		value: dataProperty{v: testValue{1}, writable: true},
+		r:     false,
+	}
+
+	props := newTestObject(Null{})
+	props.properties["x"] = property{
+		value:        dataProperty{v: descObj, writable: true},
+		r:            true,
+		enumerable:   true,
+		configurable: true,
+	}
+
+	target := newTestObject(Null{})
+	if err := DefineProperties(target, props, ownerB); err == nil {
+		t.Fatal("DefineProperties did not reject a descriptor with a non-world-readable field owned by another principal")
+	}
+	if target.HasOwnProperty("x") {
+		t.Error("DefineProperties defined \"x\" despite the permission error")
+	}
+}